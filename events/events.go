@@ -0,0 +1,125 @@
+// Package events provides a small buffered event bus that lets HTTP
+// clients subscribe to component status transitions, either by long
+// polling or via Server-Sent Events, instead of repeatedly scraping
+// /health.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of transition an Event describes.
+type Type string
+
+const (
+	ComponentUp   Type = "ComponentUp"
+	ComponentDown Type = "ComponentDown"
+	StatusChanged Type = "StatusChanged"
+	CheckFailed   Type = "CheckFailed"
+)
+
+// Event is a single typed occurrence emitted by a component check.
+type Event struct {
+	ID        uint64    `json:"id"`
+	Time      time.Time `json:"time"`
+	Type      Type      `json:"type"`
+	Component string    `json:"component"`
+	Previous  string    `json:"previous_status,omitempty"`
+	New       string    `json:"new_status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Bus is a fixed-size ring buffer of events shared by all subscribers.
+// Publishers never block on subscribers; slow subscribers simply miss
+// events once the buffer wraps, which Since reports via its gap return
+// value so callers can detect and recover from missed events.
+type Bus struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	size     int
+	events   []Event
+	nextID   uint64
+	overflow uint64 // count of events evicted from the buffer so far
+}
+
+// NewBus returns a Bus that retains at most size events. size <= 0 falls
+// back to a default of 1024.
+func NewBus(size int) *Bus {
+	if size <= 0 {
+		size = 1024
+	}
+	b := &Bus{size: size}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Publish appends a new event to the buffer, assigning it the next
+// monotonic ID, and wakes any subscribers blocked in Since.
+func (b *Bus) Publish(typ Type, component, previous, newStatus, errMsg string) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	evt := Event{
+		ID:        b.nextID,
+		Time:      time.Now().UTC(),
+		Type:      typ,
+		Component: component,
+		Previous:  previous,
+		New:       newStatus,
+		Error:     errMsg,
+	}
+	b.events = append(b.events, evt)
+	if len(b.events) > b.size {
+		dropped := len(b.events) - b.size
+		b.events = b.events[dropped:]
+		b.overflow += uint64(dropped)
+	}
+	b.cond.Broadcast()
+	return evt
+}
+
+// Since blocks until an event newer than sinceID is available or ctx is
+// done, then returns every buffered event newer than sinceID. gap is true
+// if events older than the oldest buffered event were evicted before the
+// caller could read them, meaning sinceID can no longer be trusted to
+// recover a contiguous history.
+func (b *Bus) Since(ctx context.Context, sinceID uint64) (result []Event, gap bool) {
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.cond.Broadcast()
+			b.mu.Unlock()
+		case <-stopWatch:
+		}
+	}()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		if len(b.events) > 0 && b.events[len(b.events)-1].ID > sinceID {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		default:
+		}
+		b.cond.Wait()
+	}
+
+	if len(b.events) > 0 {
+		oldest := b.events[0].ID
+		gap = sinceID < oldest-1
+	}
+	for _, e := range b.events {
+		if e.ID > sinceID {
+			result = append(result, e)
+		}
+	}
+	return result, gap
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBus_SinceReturnsOnlyNewerEvents(t *testing.T) {
+	b := NewBus(10)
+	b.Publish(ComponentUp, "db", "", "ok", "")
+	second := b.Publish(ComponentDown, "db", "ok", "down", "timeout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	evts, gap := b.Since(ctx, second.ID-1)
+	if gap {
+		t.Errorf("expected no gap, buffer never overflowed")
+	}
+	if len(evts) != 1 || evts[0].ID != second.ID {
+		t.Fatalf("expected exactly the second event, got %+v", evts)
+	}
+}
+
+func TestBus_OverflowReportsGapForNewSubscriber(t *testing.T) {
+	b := NewBus(2)
+	b.Publish(ComponentUp, "a", "", "ok", "")
+	b.Publish(ComponentUp, "b", "", "ok", "")
+	b.Publish(ComponentUp, "c", "", "ok", "") // evicts the first event
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// sinceID == 0 is what a brand-new subscriber (no prior cursor) uses;
+	// it must still report the gap left by the eviction above.
+	evts, gap := b.Since(ctx, 0)
+	if !gap {
+		t.Errorf("expected gap=true for since=0 after an overflow, got false")
+	}
+	if len(evts) != 2 {
+		t.Errorf("expected the 2 buffered events, got %d", len(evts))
+	}
+}
+
+func TestBus_SinceNoGapWithoutOverflow(t *testing.T) {
+	b := NewBus(10)
+	b.Publish(ComponentUp, "a", "", "ok", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, gap := b.Since(ctx, 0)
+	if gap {
+		t.Errorf("expected gap=false for since=0 when nothing has been evicted")
+	}
+}
+
+func TestBus_SinceBlocksUntilPublish(t *testing.T) {
+	b := NewBus(10)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan []Event, 1)
+	go func() {
+		evts, _ := b.Since(ctx, 0)
+		done <- evts
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	b.Publish(ComponentUp, "a", "", "ok", "")
+
+	select {
+	case evts := <-done:
+		if len(evts) != 1 {
+			t.Errorf("expected 1 event after publish, got %d", len(evts))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Since did not return after Publish")
+	}
+}
+
+func TestBus_SinceReturnsOnContextCancel(t *testing.T) {
+	b := NewBus(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	evts, gap := b.Since(ctx, 0)
+	if evts != nil || gap {
+		t.Errorf("expected nil events and gap=false on context timeout, got %+v, %v", evts, gap)
+	}
+}
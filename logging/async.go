@@ -0,0 +1,51 @@
+package logging
+
+// asyncSink dispatches writes to an inner Sink on a background worker so a
+// slow or unreachable sink (a stuck webhook POST, a blocked syslog daemon)
+// can never stall the caller logging through it. Once the queue fills,
+// records are dropped rather than applied with backpressure: logging must
+// stay best-effort, the same guarantee Write already makes for individual
+// sinks.
+type asyncSink struct {
+	inner Sink
+	queue chan Record
+	done  chan struct{}
+}
+
+// asyncSinkQueueSize bounds how many records can be buffered for a slow
+// sink before new ones are dropped.
+const asyncSinkQueueSize = 256
+
+func newAsyncSink(inner Sink) *asyncSink {
+	s := &asyncSink{
+		inner: inner,
+		queue: make(chan Record, asyncSinkQueueSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+	for rec := range s.queue {
+		s.inner.Write(rec)
+	}
+}
+
+// Write enqueues rec for the background worker and returns immediately.
+func (s *asyncSink) Write(rec Record) error {
+	select {
+	case s.queue <- rec:
+	default:
+	}
+	return nil
+}
+
+// Close stops accepting new records and waits for the worker to finish
+// draining whatever is already queued.
+func (s *asyncSink) Close() error {
+	close(s.queue)
+	<-s.done
+	return nil
+}
@@ -0,0 +1,284 @@
+package logging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileSink writes records as newline-delimited JSON to a current log
+// file, rotating it by size and/or time and gzip-compressing the
+// rotated-out file. Retention applies to rotated files by both age and
+// total size.
+type fileSink struct {
+	mu sync.Mutex
+
+	dir               string
+	maxSize           int64
+	rotateEvery       time.Duration
+	retentionDays     int
+	retentionMaxBytes int64
+
+	f      *os.File
+	w      *bufio.Writer
+	size   int64
+	opened time.Time
+}
+
+func newFileSink(cfg Config) (*fileSink, error) {
+	dir := cfg.Directory
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	fs := &fileSink{
+		dir:               dir,
+		maxSize:           cfg.MaxSizeBytes,
+		rotateEvery:       cfg.RotateInterval,
+		retentionDays:     cfg.RetentionDays,
+		retentionMaxBytes: cfg.RetentionMaxBytes,
+	}
+	if err := fs.openCurrent(); err != nil {
+		return nil, err
+	}
+	// Clean up anything left over from a previous run's retention policy
+	// before the first rotation under this one.
+	enforceRetention(fs.dir, fs.retentionDays, fs.retentionMaxBytes)
+	return fs, nil
+}
+
+func (fs *fileSink) currentPath() string {
+	return filepath.Join(fs.dir, "current.log")
+}
+
+func (fs *fileSink) openCurrent() error {
+	f, err := os.OpenFile(fs.currentPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	fs.f = f
+	fs.w = bufio.NewWriter(f)
+	fs.size = info.Size()
+	fs.opened = time.Now()
+	return nil
+}
+
+// Write implements Sink.
+func (fs *fileSink) Write(rec Record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.shouldRotateLocked() {
+		if err := fs.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	n, err := fs.w.Write(b)
+	if err != nil {
+		return err
+	}
+	fs.size += int64(n)
+	return fs.w.Flush()
+}
+
+func (fs *fileSink) shouldRotateLocked() bool {
+	if fs.maxSize > 0 && fs.size >= fs.maxSize {
+		return true
+	}
+	if fs.rotateEvery > 0 && time.Since(fs.opened) >= fs.rotateEvery {
+		return true
+	}
+	return false
+}
+
+func (fs *fileSink) rotateLocked() error {
+	fs.w.Flush()
+	fs.f.Close()
+
+	rotatedName := time.Now().UTC().Format("20060102T150405.000000000") + ".log.gz"
+	if err := gzipFile(fs.currentPath(), filepath.Join(fs.dir, rotatedName)); err != nil {
+		return err
+	}
+	if err := os.Remove(fs.currentPath()); err != nil {
+		return err
+	}
+	if err := fs.openCurrent(); err != nil {
+		return err
+	}
+	return enforceRetention(fs.dir, fs.retentionDays, fs.retentionMaxBytes)
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	if _, err := io.Copy(gw, in); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// enforceRetention deletes rotated .log.gz files older than
+// retentionDays (if set), then, if the remaining files still exceed
+// retentionMaxBytes (if set), deletes the oldest ones until they don't.
+func enforceRetention(dir string, retentionDays int, retentionMaxBytes int64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type rotatedFile struct {
+		path string
+		info os.FileInfo
+	}
+	var files []rotatedFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".log.gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, rotatedFile{filepath.Join(dir, e.Name()), info})
+	}
+
+	if retentionDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -retentionDays)
+		kept := files[:0]
+		for _, f := range files {
+			if f.info.ModTime().Before(cutoff) {
+				os.Remove(f.path)
+				continue
+			}
+			kept = append(kept, f)
+		}
+		files = kept
+	}
+
+	if retentionMaxBytes > 0 {
+		sort.Slice(files, func(i, j int) bool { return files[i].info.ModTime().Before(files[j].info.ModTime()) })
+		var total int64
+		for _, f := range files {
+			total += f.info.Size()
+		}
+		for len(files) > 0 && total > retentionMaxBytes {
+			os.Remove(files[0].path)
+			total -= files[0].info.Size()
+			files = files[1:]
+		}
+	}
+	return nil
+}
+
+// query scans the current log file and every retained rotated file, in
+// chronological order, for records matching component/level at or after
+// since.
+func (fs *fileSink) query(component string, since time.Time, level string) ([]Record, error) {
+	fs.mu.Lock()
+	fs.w.Flush()
+	fs.mu.Unlock()
+
+	entries, err := os.ReadDir(fs.dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if name == "current.log" || strings.HasSuffix(name, ".log.gz") {
+			paths = append(paths, filepath.Join(fs.dir, name))
+		}
+	}
+	sort.Strings(paths) // rotated files are timestamp-named, current.log sorts last
+
+	var out []Record
+	for _, p := range paths {
+		recs, err := readRecords(p)
+		if err != nil {
+			return nil, fmt.Errorf("logging: reading %s: %w", p, err)
+		}
+		for _, r := range recs {
+			if component != "" && r.Component != component {
+				continue
+			}
+			if level != "" && r.Level != level {
+				continue
+			}
+			if !since.IsZero() && r.Time.Before(since) {
+				continue
+			}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func readRecords(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+func (fs *fileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.w.Flush()
+	return fs.f.Close()
+}
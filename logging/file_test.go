@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func rotatedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".gz" {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestFileSink_RotatesOnSize(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := newFileSink(Config{Directory: dir, MaxSizeBytes: 1})
+	if err != nil {
+		t.Fatalf("newFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Write(Record{Message: "first"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fs.Write(Record{Message: "second"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got := rotatedFiles(t, dir); len(got) != 1 {
+		t.Fatalf("expected 1 rotated file after exceeding max size, got %v", got)
+	}
+}
+
+func TestFileSink_RotatedFileIsValidGzipOfWrittenRecords(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := newFileSink(Config{Directory: dir, MaxSizeBytes: 1})
+	if err != nil {
+		t.Fatalf("newFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Write(Record{Component: "db", Message: "hello"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fs.Write(Record{Component: "cache", Message: "world"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	names := rotatedFiles(t, dir)
+	if len(names) != 1 {
+		t.Fatalf("expected 1 rotated file, got %v", names)
+	}
+	records, err := readRecords(filepath.Join(dir, names[0]))
+	if err != nil {
+		t.Fatalf("readRecords on rotated gzip file: %v", err)
+	}
+	if len(records) != 1 || records[0].Component != "db" || records[0].Message != "hello" {
+		t.Fatalf("expected the rotated-out record to round-trip through gzip, got %+v", records)
+	}
+}
+
+func TestFileSink_RotatesOnInterval(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := newFileSink(Config{Directory: dir, RotateInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("newFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Write(Record{Message: "first"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := fs.Write(Record{Message: "second"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got := rotatedFiles(t, dir); len(got) != 1 {
+		t.Fatalf("expected 1 rotated file once rotate_interval elapsed, got %v", got)
+	}
+}
+
+func TestEnforceRetention_DeletesFilesOlderThanRetentionDays(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.log.gz")
+	if err := os.WriteFile(old, []byte("x"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	oldTime := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(old, oldTime, oldTime); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	fresh := filepath.Join(dir, "fresh.log.gz")
+	if err := os.WriteFile(fresh, []byte("y"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := enforceRetention(dir, 7, 0); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected old.log.gz to be deleted, stat err = %v", err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("expected fresh.log.gz to survive retention, stat err = %v", err)
+	}
+}
+
+func TestEnforceRetention_DeletesOldestUntilUnderSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"a.log.gz", "b.log.gz", "c.log.gz"}
+	times := []time.Time{
+		time.Now().Add(-3 * time.Hour),
+		time.Now().Add(-2 * time.Hour),
+		time.Now().Add(-1 * time.Hour),
+	}
+	for i, name := range names {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("1234567890"), 0644); err != nil { // 10 bytes each
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if err := os.Chtimes(p, times[i], times[i]); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+
+	// 30 bytes total; cap at 15 should evict the two oldest (a, b).
+	if err := enforceRetention(dir, 0, 15); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+
+	for _, name := range []string{"a.log.gz", "b.log.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be evicted under the size cap, stat err = %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.log.gz")); err != nil {
+		t.Errorf("expected newest file c.log.gz to survive, stat err = %v", err)
+	}
+}
+
+func TestFileSink_QueryFiltersByComponentLevelAndSince(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := newFileSink(Config{Directory: dir})
+	if err != nil {
+		t.Fatalf("newFileSink: %v", err)
+	}
+	defer fs.Close()
+
+	now := time.Now().UTC()
+	fs.Write(Record{Time: now.Add(-time.Hour), Component: "db", Level: "info", Message: "old"})
+	fs.Write(Record{Time: now, Component: "db", Level: "error", Message: "recent error"})
+	fs.Write(Record{Time: now, Component: "cache", Level: "error", Message: "other component"})
+
+	recs, err := fs.query("db", now.Add(-time.Minute), "error")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(recs) != 1 || recs[0].Message != "recent error" {
+		t.Fatalf("expected only the recent db error record, got %+v", recs)
+	}
+}
@@ -0,0 +1,198 @@
+// Package logging provides the monitor's structured logger. It's a thin
+// wrapper around log/slog: a slog.Handler fans every record out to a
+// rotating, gzip-compressed log file plus whatever other sinks are
+// configured (stdout, syslog, a webhook), and the file sink is queryable
+// after the fact so GET /logs can serve past records without SSHing to
+// the host.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Record is a single structured log entry.
+type Record struct {
+	Time      time.Time      `json:"time"`
+	Level     string         `json:"level"`
+	Message   string         `json:"msg"`
+	Component string         `json:"component,omitempty"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+}
+
+// Sink receives every record logged through a Logger.
+type Sink interface {
+	Write(Record) error
+}
+
+// Config selects and configures the enabled sinks. The rotating log file
+// is always present; Stdout, Syslog, and WebhookURL are opt-in.
+type Config struct {
+	Directory         string        `json:"directory"`
+	MaxSizeBytes      int64         `json:"max_size_bytes"`
+	RotateInterval    time.Duration `json:"rotate_interval"`
+	RetentionDays     int           `json:"retention_days"`
+	RetentionMaxBytes int64         `json:"retention_max_bytes"`
+	Stdout            bool          `json:"stdout"`
+	Syslog            bool          `json:"syslog"`
+	WebhookURL        string        `json:"webhook_url"`
+}
+
+// Logger fans every logged record out to a rotating file sink plus
+// whatever other sinks cfg enabled, through a slog.Logger.
+type Logger struct {
+	slog  *slog.Logger
+	file  *fileSink
+	async []*asyncSink
+}
+
+// New builds a Logger from cfg. Syslog and webhook sinks run on a
+// background worker (see asyncSink) so a stuck daemon or unreachable
+// webhook can't block the goroutine doing the logging; the file and
+// stdout sinks are local and fast enough to write inline.
+func New(cfg Config) (*Logger, error) {
+	file, err := newFileSink(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("logging: %w", err)
+	}
+	sinks := []Sink{file}
+	var async []*asyncSink
+	if cfg.Stdout {
+		sinks = append(sinks, stdoutSink{})
+	}
+	if cfg.Syslog {
+		s, err := newSyslogSink()
+		if err != nil {
+			return nil, fmt.Errorf("logging: %w", err)
+		}
+		a := newAsyncSink(s)
+		sinks = append(sinks, a)
+		async = append(async, a)
+	}
+	if cfg.WebhookURL != "" {
+		a := newAsyncSink(newWebhookSink(cfg.WebhookURL))
+		sinks = append(sinks, a)
+		async = append(async, a)
+	}
+	return &Logger{slog: slog.New(&sinkHandler{sinks: sinks}), file: file, async: async}, nil
+}
+
+// sinkHandler is a slog.Handler that turns each slog.Record into our own
+// Record and fans it out to every configured Sink.
+type sinkHandler struct {
+	sinks []Sink
+	attrs []slog.Attr
+}
+
+func (h *sinkHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *sinkHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]any, r.NumAttrs()+len(h.attrs))
+	for _, a := range h.attrs {
+		attrs[a.Key] = a.Value.Any()
+	}
+	var component string
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "component" {
+			component, _ = a.Value.Any().(string)
+			return true
+		}
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+	rec := Record{
+		Time:      r.Time.UTC(),
+		Level:     levelName(r.Level),
+		Message:   r.Message,
+		Component: component,
+		Attrs:     attrs,
+	}
+	for _, s := range h.sinks {
+		// Best effort: a sink outage (a down syslog daemon, an
+		// unreachable webhook) must never block or fail a health check.
+		s.Write(rec)
+	}
+	return nil
+}
+
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sinkHandler{sinks: h.sinks, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *sinkHandler) WithGroup(string) slog.Handler { return h }
+
+func levelName(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError:
+		return "error"
+	case l >= slog.LevelWarn:
+		return "warn"
+	case l >= slog.LevelDebug && l < slog.LevelInfo:
+		return "debug"
+	default:
+		return "info"
+	}
+}
+
+func (l *Logger) Debug(component, msg string, attrs map[string]any) {
+	l.slog.Debug(msg, attrsToArgs(component, attrs)...)
+}
+
+func (l *Logger) Info(component, msg string, attrs map[string]any) {
+	l.slog.Info(msg, attrsToArgs(component, attrs)...)
+}
+
+func (l *Logger) Warn(component, msg string, attrs map[string]any) {
+	l.slog.Warn(msg, attrsToArgs(component, attrs)...)
+}
+
+func (l *Logger) Error(component, msg string, attrs map[string]any) {
+	l.slog.Error(msg, attrsToArgs(component, attrs)...)
+}
+
+func attrsToArgs(component string, attrs map[string]any) []any {
+	args := make([]any, 0, 2*(len(attrs)+1))
+	if component != "" {
+		args = append(args, "component", component)
+	}
+	for k, v := range attrs {
+		args = append(args, k, v)
+	}
+	return args
+}
+
+// Infof, Warnf, and Errorf cover the plain operational messages (startup,
+// shutdown, config reloads) that don't need a component or structured
+// attrs of their own.
+func (l *Logger) Infof(format string, args ...any) {
+	l.slog.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Warnf(format string, args ...any) {
+	l.slog.Warn(fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Errorf(format string, args ...any) {
+	l.slog.Error(fmt.Sprintf(format, args...))
+}
+
+// Query returns log records for component (all components if empty) and
+// level (all levels if empty), recorded at or after since, read from the
+// current log file and any still-retained rotated files.
+func (l *Logger) Query(component string, since time.Time, level string) ([]Record, error) {
+	return l.file.query(component, since, level)
+}
+
+// Close drains and stops any background sink workers, then flushes and
+// closes the underlying log file.
+func (l *Logger) Close() error {
+	for _, a := range l.async {
+		a.Close()
+	}
+	return l.file.Close()
+}
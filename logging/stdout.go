@@ -0,0 +1,20 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// stdoutSink writes each record as a JSON line to stdout, for local runs
+// and container log collection.
+type stdoutSink struct{}
+
+func (stdoutSink) Write(rec Record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(b))
+	return err
+}
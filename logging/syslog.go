@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+// syslogSink forwards records to the local syslog daemon.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink() (*syslogSink, error) {
+	w, err := syslog.New(syslog.LOG_INFO, "p-monitoring")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(rec Record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	switch rec.Level {
+	case "error":
+		return s.w.Err(string(b))
+	case "warn":
+		return s.w.Warning(string(b))
+	case "debug":
+		return s.w.Debug(string(b))
+	default:
+		return s.w.Info(string(b))
+	}
+}
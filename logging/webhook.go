@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// webhookSink POSTs each record as JSON to a configured URL, e.g. a
+// Slack/Teams incoming webhook or a log-aggregation ingest endpoint.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Write posts rec to the webhook. Delivery failures are swallowed: a
+// down webhook must never block or fail a health check.
+func (s *webhookSink) Write(rec Record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	return nil
+}
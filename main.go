@@ -1,22 +1,37 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
-	"io"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
+
+	"p-monitoring/events"
+	"p-monitoring/logging"
+	"p-monitoring/metrics"
+	"p-monitoring/probe"
+	"p-monitoring/scheduler"
 )
 
 // Config structure for internal components
 type ComponentConfig struct {
-	Name     string `json:"name"`
-	Endpoint string `json:"endpoint"`
+	Name              string          `json:"name"`
+	Endpoint          string          `json:"endpoint"`
+	Type              string          `json:"type"`
+	Options           json.RawMessage `json:"options"`
+	IntervalSeconds   int             `json:"interval_seconds"`
+	TimeoutSeconds    int             `json:"timeout_seconds"`
+	FailureThreshold  int             `json:"failure_threshold"`
+	MaxBackoffSeconds int             `json:"max_backoff_seconds"`
 }
 
 type AppConfig struct {
@@ -25,21 +40,45 @@ type AppConfig struct {
 	LogDirectory     string            `json:"log_directory"`
 	LogRetentionDays int               `json:"log_retention_days"`
 	ListenAddress    string            `json:"listen_address"`
+	Metrics          *metrics.Config   `json:"metrics"`
+	EventBufferSize  int               `json:"event_buffer_size"`
+	MaxConcurrency   int               `json:"max_concurrency"`
+	AdminToken       string            `json:"admin_token"`
+	Logging          *logging.Config   `json:"logging"`
+}
+
+// loggingConfig builds the logging.Config for cfg, falling back to the
+// original top-level log_directory/log_retention_days fields so existing
+// configs keep working unchanged.
+func loggingConfig(cfg *AppConfig) logging.Config {
+	lc := logging.Config{}
+	if cfg.Logging != nil {
+		lc = *cfg.Logging
+	}
+	if lc.Directory == "" {
+		lc.Directory = cfg.LogDirectory
+	}
+	if lc.RetentionDays == 0 {
+		lc.RetentionDays = cfg.LogRetentionDays
+	}
+	return lc
 }
 
 // Health response structure
 type HealthComponent struct {
-	Name           string    `json:"name"`
-	Status         string    `json:"status"`
-	EndpointStatus string    `json:"endpoint_status"`
-	HTTPResult     string    `json:"http_result"`
-	LastChecked    time.Time `json:"last_checked"`
-	Error          string    `json:"error,omitempty"`
+	Name                string    `json:"name"`
+	Status              string    `json:"status"`
+	EndpointStatus      string    `json:"endpoint_status"`
+	HTTPResult          string    `json:"http_result"`
+	LastChecked         time.Time `json:"last_checked"`
+	Error               string    `json:"error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures,omitempty"`
 }
 
 type HealthResponse struct {
 	Status     string            `json:"status"`
 	Components []HealthComponent `json:"components"`
+	Version    int64             `json:"version"`
 }
 
 // In-memory status for components
@@ -68,122 +107,215 @@ func (s *StatusMap) GetAll() []HealthComponent {
 	return components
 }
 
-// Logging
-
-func setupLogger(logDir string) *log.Logger {
-	os.MkdirAll(logDir, 0755)
-	logFile := filepath.Join(logDir, time.Now().Format("2006-01-02")+".log")
-	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		log.Fatalf("Failed to open log file: %v", err)
-	}
-	return log.New(f, "", log.LstdFlags|log.LUTC|log.Lmsgprefix)
-}
-
 // Config loading
 
+// loadConfig reads, parses, and validates the config file at path.
 func loadConfig(path string) (*AppConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 	var cfg AppConfig
-	err = json.Unmarshal(data, &cfg)
-	if err != nil {
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(&cfg); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
 }
 
-// Health check logic for components with endpoints
-func checkComponents(cfg *AppConfig, statusMap *StatusMap, logger *log.Logger) {
-	client := &http.Client{Timeout: 5 * time.Second}
+// validateConfig rejects configs that would otherwise fail in confusing
+// ways later, such as components with no name/endpoint or duplicate
+// names. It's also what POST /config/reload and POST /components run
+// new config through before handing it to the Registry.
+func validateConfig(cfg *AppConfig) error {
+	seen := make(map[string]bool, len(cfg.Components))
 	for _, c := range cfg.Components {
-		status := "unknown"
-		endpointStatus := "not ok"
-		httpResult := ""
-		errMsg := ""
-		resp, err := client.Get(c.Endpoint)
-		if err != nil {
-			status = "unreachable"
-			httpResult = err.Error()
-			errMsg = err.Error()
+		if c.Name == "" {
+			return fmt.Errorf("component missing required \"name\" field")
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("duplicate component name %q", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Endpoint == "" {
+			return fmt.Errorf("component %q missing required \"endpoint\" field", c.Name)
+		}
+	}
+	return nil
+}
+
+// checkComponents runs an immediate, synchronous check of every
+// component using a default per-check timeout. It exists alongside the
+// scheduler-driven path in startHealthChecks for callers, such as tests,
+// that want a one-shot check of the whole fleet.
+func checkComponents(cfg *AppConfig, statusMap *StatusMap, logger *logging.Logger, sink metrics.Sink, bus *events.Bus) {
+	for _, c := range cfg.Components {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		runComponentCheck(ctx, c, statusMap, logger, sink, bus, nil)
+		cancel()
+	}
+}
+
+// runComponentCheck probes a single component and records the outcome in
+// statusMap, the metrics sink, the event bus, and the log, returning
+// whether the component was up. If current is non-nil and reports false
+// once the probe completes, this run has been superseded (the component
+// was removed or reconfigured while the probe was in flight) and nothing
+// is recorded, so a slow in-flight check can never resurrect a removed
+// component in statusMap or clobber a newer run's result.
+func runComponentCheck(ctx context.Context, c ComponentConfig, statusMap *StatusMap, logger *logging.Logger, sink metrics.Sink, bus *events.Bus, current func() bool) bool {
+	status := "unknown"
+	endpointStatus := "not ok"
+	httpResult := ""
+	errMsg := ""
+	httpStatusCode := 0
+
+	prober, err := probe.New(probe.Config{Name: c.Name, Endpoint: c.Endpoint, Type: c.Type, Options: c.Options})
+	var latency time.Duration
+	if err != nil {
+		status = "invalid_config"
+		errMsg = err.Error()
+	} else {
+		start := time.Now()
+		result := prober.Probe(ctx)
+		latency = time.Since(start)
+
+		httpResult = result.Detail
+		httpStatusCode = result.HTTPStatus
+		if result.Up {
+			status = "ok"
+			endpointStatus = "ok"
 		} else {
-			httpResult = resp.Status
-			defer resp.Body.Close()
-			// Accept HTTP 200 as healthy, or plain text 'ok' (case-insensitive, trimmed)
-			var health struct {
-				Status string `json:"status"`
+			status = "unreachable"
+			if result.Err != nil {
+				errMsg = result.Err.Error()
 			}
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			decErr := json.Unmarshal(bodyBytes, &health)
-			if decErr == nil && health.Status == "ok" {
-				status = "ok"
-				endpointStatus = "ok"
-			} else {
-				// Try plain text
-				bodyStr := string(bodyBytes)
-				if resp.StatusCode == 200 && (len(bodyStr) == 0 || trimToOk(bodyStr)) {
-					status = "ok"
-					endpointStatus = "ok"
-				} else if resp.StatusCode == 200 {
-					status = "ok"
-					endpointStatus = "ok"
-				} else {
-					status = "invalid_response"
-					errMsg = decErr.Error()
-				}
+		}
+	}
+
+	if current != nil && !current() {
+		return status == "ok"
+	}
+
+	statusMap.mu.Lock()
+	previousStatus := statusMap.Components[c.Name].Status
+	consecutiveFailures := 0
+	if status != "ok" {
+		consecutiveFailures = statusMap.Components[c.Name].ConsecutiveFailures + 1
+	}
+	statusMap.Components[c.Name] = HealthComponent{
+		Name:                c.Name,
+		Status:              status,
+		EndpointStatus:      endpointStatus,
+		HTTPResult:          httpResult,
+		LastChecked:         time.Now(),
+		Error:               errMsg,
+		ConsecutiveFailures: consecutiveFailures,
+	}
+	statusMap.mu.Unlock()
+	sink.RecordCheck(c.Name, status == "ok", latency.Seconds(), httpStatusCode, consecutiveFailures)
+	publishTransition(bus, c.Name, previousStatus, status, errMsg)
+	logger.Info(c.Name, "component checked", map[string]any{
+		"status":          status,
+		"endpoint_status": endpointStatus,
+		"http_result":     httpResult,
+		"error":           errMsg,
+	})
+
+	return status == "ok"
+}
+
+// publishTransition emits the typed events that describe how a
+// component's status changed between two consecutive checks.
+func publishTransition(bus *events.Bus, component, previousStatus, newStatus, errMsg string) {
+	if newStatus != "ok" {
+		bus.Publish(events.CheckFailed, component, previousStatus, newStatus, errMsg)
+	}
+	if previousStatus == newStatus {
+		return
+	}
+	bus.Publish(events.StatusChanged, component, previousStatus, newStatus, errMsg)
+	switch {
+	case previousStatus != "ok" && newStatus == "ok":
+		bus.Publish(events.ComponentUp, component, previousStatus, newStatus, "")
+	case previousStatus == "ok" && newStatus != "ok":
+		bus.Publish(events.ComponentDown, component, previousStatus, newStatus, errMsg)
+	}
+}
+
+// Periodic health checks for internal components. Each component runs
+// on its own goroutine and interval, with startup jitter, a per-component
+// timeout, and circuit breaking, all governed by a Registry so that
+// components can later be added, removed, or changed without restarting
+// the process.
+func startHealthChecks(cfg *AppConfig, statusMap *StatusMap, logger *logging.Logger, sink metrics.Sink, bus *events.Bus) *Registry {
+	sched := scheduler.New(cfg.MaxConcurrency)
+
+	defaultInterval := time.Duration(cfg.CheckInterval) * time.Second
+	if defaultInterval <= 0 {
+		defaultInterval = 30 * time.Second
+	}
+
+	registry := NewRegistry(sched, statusMap, logger, sink, bus, defaultInterval)
+	registry.Apply(cfg.Components)
+	return registry
+}
+
+// HTTP server with /health, /events(/stream), the authenticated
+// config/component management endpoints, and, when enabled, /metrics.
+func startHTTPServer(statusMap *StatusMap, addr string, logger *logging.Logger, sink metrics.Sink, bus *events.Bus, registry *Registry, cfg *AppConfig, configPath string) {
+	if cfg.AdminToken == "" {
+		logger.Warnf("admin_token is unset: /config/reload, POST /components, and DELETE /components/* are exposed with no authentication")
+	}
+	if exposer, ok := sink.(interface{ Handler() http.Handler }); ok {
+		http.Handle("/metrics", exposer.Handler())
+	}
+	http.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		sinceID, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+		timeout := 30 * time.Second
+		if t := r.URL.Query().Get("timeout"); t != "" {
+			if d, err := time.ParseDuration(t); err == nil {
+				timeout = d
 			}
 		}
-		statusMap.mu.Lock()
-		statusMap.Components[c.Name] = HealthComponent{
-			Name:           c.Name,
-			Status:         status,
-			EndpointStatus: endpointStatus,
-			HTTPResult:     httpResult,
-			LastChecked:    time.Now(),
-			Error:          errMsg,
-		}
-		statusMap.mu.Unlock()
-		logEntry := struct {
-			Time           time.Time `json:"time"`
-			Component      string    `json:"component"`
-			Status         string    `json:"status"`
-			EndpointStatus string    `json:"endpoint_status"`
-			HTTPResult     string    `json:"http_result"`
-			Error          string    `json:"error,omitempty"`
-		}{
-			Time:           time.Now().UTC(),
-			Component:      c.Name,
-			Status:         status,
-			EndpointStatus: endpointStatus,
-			HTTPResult:     httpResult,
-			Error:          errMsg,
-		}
-		b, _ := json.Marshal(logEntry)
-		logger.Println(string(b))
-	}
-}
-
-// Helper to check if a string is 'ok' (case-insensitive, trimmed)
-func trimToOk(s string) bool {
-	trimmed := strings.TrimSpace(strings.ToLower(s))
-	return trimmed == "ok"
-}
-
-// Periodic health check for internal components
-func startHealthChecks(cfg *AppConfig, statusMap *StatusMap, logger *log.Logger) {
-	interval := time.Duration(cfg.CheckInterval) * time.Second
-	go func() {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		evts, gap := bus.Since(ctx, sinceID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Events []events.Event `json:"events"`
+			Gap    bool           `json:"gap,omitempty"`
+		}{Events: evts, Gap: gap})
+	})
+	http.HandleFunc("/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		sinceID, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+		ctx := r.Context()
 		for {
-			checkComponents(cfg, statusMap, logger)
-			time.Sleep(interval)
+			evts, gap := bus.Since(ctx, sinceID)
+			if gap {
+				fmt.Fprintf(w, "event: gap\ndata: {}\n\n")
+			}
+			if len(evts) == 0 {
+				return
+			}
+			for _, e := range evts {
+				b, _ := json.Marshal(e)
+				fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.ID, b)
+				sinceID = e.ID
+			}
+			flusher.Flush()
 		}
-	}()
-}
-
-// HTTP server with /health endpoint
-func startHTTPServer(statusMap *StatusMap, addr string, logger *log.Logger) {
+	})
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		components := statusMap.GetAll()
@@ -197,30 +329,108 @@ func startHTTPServer(statusMap *StatusMap, addr string, logger *log.Logger) {
 		resp := HealthResponse{
 			Status:     status,
 			Components: components,
+			Version:    registry.Version(),
 		}
+		w.Header().Set("ETag", fmt.Sprintf("%q", strconv.FormatInt(resp.Version, 10)))
 		json.NewEncoder(w).Encode(resp)
 	})
-	logger.Printf("HTTP server listening on %s", addr)
-	log.Fatal(http.ListenAndServe(addr, nil))
-}
 
-// Log retention cleanup
+	http.HandleFunc("/logs", func(w http.ResponseWriter, r *http.Request) {
+		component := r.URL.Query().Get("component")
+		level := r.URL.Query().Get("level")
+		var since time.Time
+		if s := r.URL.Query().Get("since"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				http.Error(w, "invalid \"since\": "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			since = t
+		}
+		records, err := logger.Query(component, since, level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+		for _, rec := range records {
+			if err := enc.Encode(rec); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	})
 
-func cleanupLogs(logDir string, retentionDays int, logger *log.Logger) {
-	cutoff := time.Now().AddDate(0, 0, -retentionDays)
-	files, err := os.ReadDir(logDir)
-	if err != nil {
-		logger.Printf("Failed to read log dir: %v", err)
-		return
-	}
-	for _, f := range files {
-		info, err := f.Info()
+	http.HandleFunc("/config/reload", requireAdminToken(cfg.AdminToken, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		newCfg, err := loadConfig(configPath)
 		if err != nil {
-			continue
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		registry.Apply(newCfg.Components)
+		logger.Infof("config reloaded from %s via /config/reload", configPath)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Version int64 `json:"version"`
+		}{registry.Version()})
+	}))
+
+	http.HandleFunc("/components", requireAdminToken(cfg.AdminToken, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
-		if info.Mode().IsRegular() && info.ModTime().Before(cutoff) {
-			os.Remove(filepath.Join(logDir, f.Name()))
+		var c ComponentConfig
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+		if err := validateConfig(&AppConfig{Components: []ComponentConfig{c}}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		registry.Add(c)
+		logger.Infof("component %q registered via /components", c.Name)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	http.HandleFunc("/components/", requireAdminToken(cfg.AdminToken, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/components/")
+		if name == "" || !registry.Remove(name) {
+			http.Error(w, "component not found", http.StatusNotFound)
+			return
+		}
+		logger.Infof("component %q removed via /components", name)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	logger.Infof("HTTP server listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// requireAdminToken wraps next so it only runs when the request carries
+// the configured admin token as a bearer token. An empty token disables
+// the check entirely; startHTTPServer logs a loud startup warning in that
+// case since these are the monitor's only write endpoints.
+func requireAdminToken(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
 	}
 }
 
@@ -232,14 +442,40 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config from %s: %v", *configPath, err)
 	}
-	logger := setupLogger(cfg.LogDirectory)
+	logger, err := logging.New(loggingConfig(cfg))
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
 	statusMap := NewStatusMap()
+	sink := metrics.NewSink(cfg.Metrics)
+	bus := events.NewBus(cfg.EventBufferSize)
+
+	registry := startHealthChecks(cfg, statusMap, logger, sink, bus)
+	watchForReload(*configPath, registry, logger)
 
-	cleanupLogs(cfg.LogDirectory, cfg.LogRetentionDays, logger)
-	startHealthChecks(cfg, statusMap, logger)
 	addr := cfg.ListenAddress
 	if addr == "" {
 		addr = ":8080"
 	}
-	startHTTPServer(statusMap, addr, logger)
+	startHTTPServer(statusMap, addr, logger, sink, bus, registry, cfg, *configPath)
+}
+
+// watchForReload reloads the config file and re-applies it to registry
+// every time the process receives SIGHUP, so operators can add/remove
+// components by editing the config file and signalling the process
+// instead of restarting it.
+func watchForReload(configPath string, registry *Registry, logger *logging.Logger) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			cfg, err := loadConfig(configPath)
+			if err != nil {
+				logger.Errorf("SIGHUP: failed to reload config from %s: %v", configPath, err)
+				continue
+			}
+			registry.Apply(cfg.Components)
+			logger.Infof("SIGHUP: config reloaded from %s, version now %d", configPath, registry.Version())
+		}
+	}()
 }
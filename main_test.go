@@ -2,12 +2,14 @@ package main
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strconv"
 	"testing"
+
+	"p-monitoring/events"
+	"p-monitoring/logging"
+	"p-monitoring/metrics"
 )
 
 func TestStatusMap_UpdateAndGetAll(t *testing.T) {
@@ -56,12 +58,15 @@ func TestHealthEndpoint(t *testing.T) {
 			{Name: "cache", Endpoint: cacheSrv.URL},
 		},
 		CheckInterval:    1,
-		LogDirectory:     "/tmp",
+		LogDirectory:     t.TempDir(),
 		LogRetentionDays: 1,
 	}
 	statusMap := NewStatusMap()
-	logger := log.New(os.Stdout, "", 0)
-	checkComponents(cfg, statusMap, logger)
+	logger, err := logging.New(loggingConfig(cfg))
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+	checkComponents(cfg, statusMap, logger, metrics.NewSink(nil), events.NewBus(0))
 
 	req := httptest.NewRequest("GET", "/health", nil)
 	w := httptest.NewRecorder()
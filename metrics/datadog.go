@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"net"
+)
+
+// DatadogConfig enables the DogStatsD sink, which fires gauges at a local
+// Datadog agent (or any StatsD-compatible collector) over UDP.
+type DatadogConfig struct {
+	Address   string `json:"address"`   // host:port of the DogStatsD listener, e.g. "127.0.0.1:8125"
+	Namespace string `json:"namespace"` // optional metric name prefix, e.g. "p_monitoring"
+}
+
+// DatadogSink sends per-check gauges to a DogStatsD listener. Sends are
+// fire-and-forget UDP, matching how StatsD clients are expected to behave:
+// a dropped metric should never slow down or fail a health check.
+type DatadogSink struct {
+	namespace string
+	conn      net.Conn
+}
+
+// NewDatadogSink dials cfg.Address (UDP, so this never blocks on the
+// network) and returns a sink that writes to it.
+func NewDatadogSink(cfg *DatadogConfig) *DatadogSink {
+	conn, err := net.Dial("udp", cfg.Address)
+	if err != nil {
+		log.Printf("metrics: datadog sink: failed to dial %s: %v", cfg.Address, err)
+	}
+	return &DatadogSink{namespace: cfg.Namespace, conn: conn}
+}
+
+// RecordCheck implements Sink.
+func (d *DatadogSink) RecordCheck(component string, up bool, latencySeconds float64, httpStatus int, consecutiveFailures int) {
+	upVal := 0
+	if up {
+		upVal = 1
+	}
+	tag := fmt.Sprintf("#component:%s", component)
+	d.gauge("component.up", float64(upVal), tag)
+	d.gauge("component.last_latency_seconds", latencySeconds, tag)
+	d.gauge("component.consecutive_failures", float64(consecutiveFailures), tag)
+	if httpStatus != 0 {
+		d.gauge("component.last_http_status", float64(httpStatus), tag)
+	}
+}
+
+func (d *DatadogSink) gauge(name string, value float64, tag string) {
+	if d.conn == nil {
+		return
+	}
+	metric := name
+	if d.namespace != "" {
+		metric = d.namespace + "." + name
+	}
+	line := fmt.Sprintf("%s:%v|g|%s", metric, value, tag)
+	d.conn.Write([]byte(line))
+}
@@ -0,0 +1,55 @@
+// Package metrics provides a small, pluggable abstraction for exporting
+// component health observations to a metrics backend. Callers record
+// observations through the Sink interface; which backend(s) actually
+// receive them is a matter of configuration.
+package metrics
+
+// Sink receives observations recorded for every component probe.
+type Sink interface {
+	// RecordCheck records the outcome of a single component check: whether
+	// it was up, how long the probe took, the HTTP status code observed
+	// (0 if not applicable), and the current consecutive-failure streak.
+	RecordCheck(component string, up bool, latencySeconds float64, httpStatus int, consecutiveFailures int)
+}
+
+// Config selects and configures the enabled metrics sinks. Any number of
+// backends may be enabled at once; observations are fanned out to all of
+// them.
+type Config struct {
+	Prometheus *PrometheusConfig `json:"prometheus"`
+	Datadog    *DatadogConfig    `json:"datadog"`
+}
+
+// NewSink builds a Sink from cfg, fanning out to every backend that is
+// configured. A nil cfg, or one with nothing enabled, yields a no-op sink
+// so callers never need to nil-check.
+func NewSink(cfg *Config) Sink {
+	var sinks []Sink
+	if cfg != nil && cfg.Prometheus != nil {
+		sinks = append(sinks, NewPrometheusSink())
+	}
+	if cfg != nil && cfg.Datadog != nil {
+		sinks = append(sinks, NewDatadogSink(cfg.Datadog))
+	}
+	switch len(sinks) {
+	case 0:
+		return noopSink{}
+	case 1:
+		return sinks[0]
+	default:
+		return multiSink(sinks)
+	}
+}
+
+type noopSink struct{}
+
+func (noopSink) RecordCheck(component string, up bool, latencySeconds float64, httpStatus int, consecutiveFailures int) {
+}
+
+type multiSink []Sink
+
+func (m multiSink) RecordCheck(component string, up bool, latencySeconds float64, httpStatus int, consecutiveFailures int) {
+	for _, s := range m {
+		s.RecordCheck(component, up, latencySeconds, httpStatus, consecutiveFailures)
+	}
+}
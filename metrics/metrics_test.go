@@ -0,0 +1,32 @@
+package metrics
+
+import "testing"
+
+func TestNewSink_NilConfigYieldsNoop(t *testing.T) {
+	s := NewSink(nil)
+	if _, ok := s.(noopSink); !ok {
+		t.Fatalf("expected a nil Config to yield noopSink, got %T", s)
+	}
+	// Must never panic even with nothing configured.
+	s.RecordCheck("db", true, 0.1, 200, 0)
+}
+
+func TestNewSink_SingleBackendIsUnwrapped(t *testing.T) {
+	s := NewSink(&Config{Prometheus: &PrometheusConfig{}})
+	if _, ok := s.(*PrometheusSink); !ok {
+		t.Fatalf("expected a single configured backend to be returned directly, got %T", s)
+	}
+}
+
+func TestNewSink_MultipleBackendsFanOut(t *testing.T) {
+	s := NewSink(&Config{Prometheus: &PrometheusConfig{}, Datadog: &DatadogConfig{Address: "127.0.0.1:0"}})
+	m, ok := s.(multiSink)
+	if !ok {
+		t.Fatalf("expected multiple configured backends to fan out via multiSink, got %T", s)
+	}
+	if len(m) != 2 {
+		t.Errorf("expected 2 backends in the fan-out, got %d", len(m))
+	}
+	// RecordCheck must reach every backend without panicking.
+	m.RecordCheck("db", false, 0.2, 503, 3)
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// PrometheusConfig enables the built-in Prometheus text-format sink. It has
+// no options of its own today; it exists so config parsing has a stable
+// place to add them (namespace, extra labels, ...) without breaking the
+// `metrics.prometheus` key.
+type PrometheusConfig struct{}
+
+// PrometheusSink accumulates per-component gauges and process-level
+// counters in memory and renders them in the Prometheus text exposition
+// format on demand via Handler.
+type PrometheusSink struct {
+	mu sync.Mutex
+
+	up                  map[string]float64
+	lastLatencySeconds  map[string]float64
+	lastHTTPStatus      map[string]float64
+	consecutiveFailures map[string]float64
+
+	totalChecks   int64
+	totalFailures int64
+}
+
+// NewPrometheusSink returns an empty PrometheusSink ready to record checks.
+func NewPrometheusSink() *PrometheusSink {
+	return &PrometheusSink{
+		up:                  make(map[string]float64),
+		lastLatencySeconds:  make(map[string]float64),
+		lastHTTPStatus:      make(map[string]float64),
+		consecutiveFailures: make(map[string]float64),
+	}
+}
+
+// RecordCheck implements Sink.
+func (p *PrometheusSink) RecordCheck(component string, up bool, latencySeconds float64, httpStatus int, consecutiveFailures int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if up {
+		p.up[component] = 1
+	} else {
+		p.up[component] = 0
+		p.totalFailures++
+	}
+	p.lastLatencySeconds[component] = latencySeconds
+	p.lastHTTPStatus[component] = float64(httpStatus)
+	p.consecutiveFailures[component] = float64(consecutiveFailures)
+	p.totalChecks++
+}
+
+// Handler returns an http.Handler suitable for mounting at /metrics.
+func (p *PrometheusSink) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		p.writeTo(w)
+	})
+}
+
+func (p *PrometheusSink) writeTo(w http.ResponseWriter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	writeGaugeFamily(w, "p_monitoring_component_up", "Whether the component is up (1) or down (0).", p.up)
+	writeGaugeFamily(w, "p_monitoring_component_last_latency_seconds", "Latency of the most recent probe, in seconds.", p.lastLatencySeconds)
+	writeGaugeFamily(w, "p_monitoring_component_last_http_status", "HTTP status code of the most recent probe.", p.lastHTTPStatus)
+	writeGaugeFamily(w, "p_monitoring_component_consecutive_failures", "Number of consecutive failed probes.", p.consecutiveFailures)
+
+	fmt.Fprintf(w, "# HELP p_monitoring_checks_total Total number of component checks performed.\n")
+	fmt.Fprintf(w, "# TYPE p_monitoring_checks_total counter\n")
+	fmt.Fprintf(w, "p_monitoring_checks_total %d\n", p.totalChecks)
+
+	fmt.Fprintf(w, "# HELP p_monitoring_failures_total Total number of failed component checks.\n")
+	fmt.Fprintf(w, "# TYPE p_monitoring_failures_total counter\n")
+	fmt.Fprintf(w, "p_monitoring_failures_total %d\n", p.totalFailures)
+}
+
+func writeGaugeFamily(w http.ResponseWriter, name, help string, values map[string]float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	names := make([]string, 0, len(values))
+	for component := range values {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+	for _, component := range names {
+		fmt.Fprintf(w, "%s{component=%q} %v\n", name, component, values[component])
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusSink_HandlerRendersRecordedChecks(t *testing.T) {
+	s := NewPrometheusSink()
+	s.RecordCheck("db", true, 0.25, 200, 0)
+	s.RecordCheck("cache", false, 1.5, 503, 4)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, req)
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`p_monitoring_component_up{component="db"} 1`,
+		`p_monitoring_component_up{component="cache"} 0`,
+		`p_monitoring_component_last_http_status{component="cache"} 503`,
+		`p_monitoring_component_consecutive_failures{component="cache"} 4`,
+		"p_monitoring_checks_total 2",
+		"p_monitoring_failures_total 1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected rendered metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected a text/plain Content-Type, got %q", ct)
+	}
+}
+
+func TestPrometheusSink_RecordCheckOverwritesPreviousValuePerComponent(t *testing.T) {
+	s := NewPrometheusSink()
+	s.RecordCheck("db", false, 1.0, 500, 1)
+	s.RecordCheck("db", true, 0.1, 200, 0)
+
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	if !strings.Contains(body, `p_monitoring_component_up{component="db"} 1`) {
+		t.Errorf("expected the latest check's up=1 to win, got:\n%s", body)
+	}
+	if strings.Count(body, `component="db"`) != 4 {
+		t.Errorf("expected exactly one gauge line per family for db (no duplicate accumulation), got:\n%s", body)
+	}
+}
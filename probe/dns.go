@@ -0,0 +1,65 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DNSOptions configures the DNS prober, which resolves cfg.Endpoint (a
+// hostname) and optionally checks for an expected record value.
+type DNSOptions struct {
+	RecordType    string `json:"record_type"`    // "A" (default) or "CNAME"
+	ExpectedValue string `json:"expected_value"` // substring expected among the resolved values; empty means "resolves at all"
+}
+
+type dnsProber struct {
+	host     string
+	opts     DNSOptions
+	resolver *net.Resolver
+}
+
+func newDNSProber(cfg Config) (Prober, error) {
+	var opts DNSOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: dns: invalid options: %w", err)
+		}
+	}
+	if opts.RecordType == "" {
+		opts.RecordType = "A"
+	}
+	return &dnsProber{host: cfg.Endpoint, opts: opts, resolver: net.DefaultResolver}, nil
+}
+
+func (p *dnsProber) Probe(ctx context.Context) Result {
+	var values []string
+	var err error
+	switch strings.ToUpper(p.opts.RecordType) {
+	case "CNAME":
+		var cname string
+		cname, err = p.resolver.LookupCNAME(ctx, p.host)
+		values = []string{cname}
+	default:
+		values, err = p.resolver.LookupHost(ctx, p.host)
+	}
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: err.Error()}
+	}
+
+	if p.opts.ExpectedValue != "" {
+		found := false
+		for _, v := range values {
+			if strings.Contains(v, p.opts.ExpectedValue) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return Result{Up: false, Detail: strings.Join(values, ","), Err: fmt.Errorf("expected_value %q not found in %v", p.opts.ExpectedValue, values)}
+		}
+	}
+	return Result{Up: true, Detail: strings.Join(values, ",")}
+}
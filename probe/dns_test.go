@@ -0,0 +1,39 @@
+package probe
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDNSProber_ResolvesLocalhost(t *testing.T) {
+	p, err := New(Config{Endpoint: "localhost", Type: "dns"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if result := p.Probe(ctx); !result.Up {
+		t.Errorf("expected localhost to resolve, got %+v", result)
+	}
+}
+
+func TestDNSProber_ExpectedValueMustBePresent(t *testing.T) {
+	p, err := New(Config{Endpoint: "localhost", Type: "dns", Options: mustJSON(t, `{"expected_value":"127.0.0.1"}`)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if result := p.Probe(ctx); !result.Up {
+		t.Errorf("expected localhost to resolve to 127.0.0.1, got %+v", result)
+	}
+
+	miss, err := New(Config{Endpoint: "localhost", Type: "dns", Options: mustJSON(t, `{"expected_value":"10.255.255.255"}`)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := miss.Probe(ctx); result.Up {
+		t.Errorf("expected a mismatched expected_value to report unhealthy, got %+v", result)
+	}
+}
@@ -0,0 +1,49 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExecOptions configures the exec prober, which runs an external command
+// or script and treats a zero exit status as healthy.
+type ExecOptions struct {
+	Command []string      `json:"command"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+type execProber struct {
+	opts ExecOptions
+}
+
+func newExecProber(cfg Config) (Prober, error) {
+	var opts ExecOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: exec: invalid options: %w", err)
+		}
+	}
+	if len(opts.Command) == 0 {
+		return nil, fmt.Errorf("probe: exec: options.command must not be empty")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &execProber{opts: opts}, nil
+}
+
+func (p *execProber) Probe(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.opts.Command[0], p.opts.Command[1:]...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: strings.TrimSpace(string(out))}
+	}
+	return Result{Up: true, Detail: strings.TrimSpace(string(out))}
+}
@@ -0,0 +1,53 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GRPCOptions configures the gRPC Health Checking Protocol prober.
+type GRPCOptions struct {
+	Service string        `json:"service"` // service name to check; empty checks overall server health
+	Timeout time.Duration `json:"timeout"`
+}
+
+type grpcProber struct {
+	endpoint string
+	opts     GRPCOptions
+}
+
+func newGRPCProber(cfg Config) (Prober, error) {
+	var opts GRPCOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: grpc: invalid options: %w", err)
+		}
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &grpcProber{endpoint: cfg.Endpoint, opts: opts}, nil
+}
+
+func (p *grpcProber) Probe(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, p.endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: err.Error()}
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.opts.Service})
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: err.Error()}
+	}
+	return Result{Up: resp.Status == healthpb.HealthCheckResponse_SERVING, Detail: resp.Status.String()}
+}
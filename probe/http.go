@@ -0,0 +1,117 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// HTTPOptions configures the HTTP prober. Every field is optional; the
+// zero value reproduces the original behavior of accepting a
+// {"status":"ok"} JSON body with any status code, or any HTTP 200.
+type HTTPOptions struct {
+	Method            string            `json:"method"`
+	Headers           map[string]string `json:"headers"`
+	ExpectedStatus    int               `json:"expected_status"`
+	ExpectedBodyRegex string            `json:"expected_body_regex"`
+	TLSCertFile       string            `json:"tls_cert_file"`
+	TLSKeyFile        string            `json:"tls_key_file"`
+	Timeout           time.Duration     `json:"timeout"`
+}
+
+type httpProber struct {
+	endpoint string
+	opts     HTTPOptions
+	client   *http.Client
+	bodyRe   *regexp.Regexp
+}
+
+func newHTTPProber(cfg Config) (Prober, error) {
+	var opts HTTPOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: http: invalid options: %w", err)
+		}
+	}
+	if opts.Method == "" {
+		opts.Method = http.MethodGet
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	var bodyRe *regexp.Regexp
+	if opts.ExpectedBodyRegex != "" {
+		re, err := regexp.Compile(opts.ExpectedBodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("probe: http: invalid expected_body_regex: %w", err)
+		}
+		bodyRe = re
+	}
+
+	transport := http.DefaultTransport
+	if opts.TLSCertFile != "" || opts.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("probe: http: loading client cert: %w", err)
+		}
+		transport = &http.Transport{TLSClientConfig: &tls.Config{Certificates: []tls.Certificate{cert}}}
+	}
+
+	return &httpProber{
+		endpoint: cfg.Endpoint,
+		opts:     opts,
+		client:   &http.Client{Timeout: opts.Timeout, Transport: transport},
+		bodyRe:   bodyRe,
+	}, nil
+}
+
+func (p *httpProber) Probe(ctx context.Context) Result {
+	req, err := http.NewRequestWithContext(ctx, p.opts.Method, p.endpoint, nil)
+	if err != nil {
+		return Result{Err: err, Detail: err.Error()}
+	}
+	for k, v := range p.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Result{Err: err, Detail: err.Error()}
+	}
+	defer resp.Body.Close()
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	// Zero-value compat path: expected_status and expected_body_regex are
+	// both unset, so reproduce the original status-code-agnostic check
+	// this prober replaced, a {"status":"ok"} JSON body or plain HTTP 200.
+	if p.opts.ExpectedStatus == 0 && p.bodyRe == nil {
+		var health struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal(bodyBytes, &health) == nil && health.Status == "ok" {
+			return Result{Up: true, HTTPStatus: resp.StatusCode, Detail: resp.Status}
+		}
+		if resp.StatusCode == http.StatusOK {
+			return Result{Up: true, HTTPStatus: resp.StatusCode, Detail: resp.Status}
+		}
+		return Result{Up: false, HTTPStatus: resp.StatusCode, Detail: resp.Status}
+	}
+
+	expectedStatus := p.opts.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if resp.StatusCode != expectedStatus {
+		return Result{Up: false, HTTPStatus: resp.StatusCode, Detail: resp.Status}
+	}
+	if p.bodyRe != nil && !p.bodyRe.Match(bodyBytes) {
+		return Result{Up: false, HTTPStatus: resp.StatusCode, Detail: resp.Status, Err: fmt.Errorf("response body did not match expected_body_regex")}
+	}
+	return Result{Up: true, HTTPStatus: resp.StatusCode, Detail: resp.Status}
+}
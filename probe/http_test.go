@@ -0,0 +1,100 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProber_DefaultAcceptsStatusOKBodyRegardlessOfStatusCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Endpoint: srv.URL, Type: "http"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	result := p.Probe(context.Background())
+	if !result.Up {
+		t.Errorf("expected a {\"status\":\"ok\"} body to be healthy regardless of HTTP status, got %+v", result)
+	}
+}
+
+func TestHTTPProber_DefaultAcceptsPlain200(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Endpoint: srv.URL, Type: "http"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); !result.Up {
+		t.Errorf("expected plain HTTP 200 to be healthy, got %+v", result)
+	}
+}
+
+func TestHTTPProber_DefaultRejectsNon200WithoutOkBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Endpoint: srv.URL, Type: "http"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); result.Up {
+		t.Errorf("expected a 500 with a non-ok body to be unhealthy, got %+v", result)
+	}
+}
+
+func TestHTTPProber_ExpectedStatusGatesResultWhenConfigured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Endpoint: srv.URL, Type: "http", Options: mustJSON(t, `{"expected_status":503}`)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); !result.Up {
+		t.Errorf("expected the configured 503 expected_status to match and report healthy, got %+v", result)
+	}
+}
+
+func TestHTTPProber_ExpectedBodyRegex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("version 1.2.3"))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Endpoint: srv.URL, Type: "http", Options: mustJSON(t, `{"expected_body_regex":"^version \\d+\\.\\d+\\.\\d+$"}`)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); !result.Up {
+		t.Errorf("expected body matching expected_body_regex to report healthy, got %+v", result)
+	}
+
+	mismatch, err := New(Config{Endpoint: srv.URL, Type: "http", Options: mustJSON(t, `{"expected_body_regex":"^nope$"}`)})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := mismatch.Probe(context.Background()); result.Up {
+		t.Errorf("expected a non-matching expected_body_regex to report unhealthy, got %+v", result)
+	}
+}
+
+func mustJSON(t *testing.T, s string) []byte {
+	t.Helper()
+	return []byte(s)
+}
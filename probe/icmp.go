@@ -0,0 +1,57 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// ICMPOptions configures the ICMP prober, which shells out to the system
+// ping binary rather than opening a raw socket, so it works without extra
+// privileges on any platform.
+type ICMPOptions struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+type icmpProber struct {
+	host    string
+	timeout time.Duration
+}
+
+func newICMPProber(cfg Config) (Prober, error) {
+	var opts ICMPOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: icmp: invalid options: %w", err)
+		}
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &icmpProber{host: cfg.Endpoint, timeout: opts.Timeout}, nil
+}
+
+func (p *icmpProber) Probe(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	waitSeconds := int(p.timeout.Seconds())
+	if waitSeconds < 1 {
+		waitSeconds = 1
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "ping", "-n", "1", "-w", fmt.Sprintf("%d", waitSeconds*1000), p.host)
+	} else {
+		cmd = exec.CommandContext(ctx, "ping", "-c", "1", "-W", fmt.Sprintf("%d", waitSeconds), p.host)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: string(out)}
+	}
+	return Result{Up: true, Detail: "ping ok"}
+}
@@ -0,0 +1,55 @@
+// Package probe defines the pluggable health-check strategies a component
+// can use. checkComponents no longer speaks HTTP directly; instead it
+// builds a Prober from a component's configured type and asks it to
+// Probe.
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Result is the outcome of a single Probe call.
+type Result struct {
+	Up         bool   // whether the component is considered healthy
+	HTTPStatus int    // HTTP status code observed, or 0 if not applicable
+	Detail     string // short human-readable detail, e.g. "200 OK" or "tcp connect ok"
+	Err        error  // non-nil if the probe itself failed; an unhealthy result may leave it nil
+}
+
+// Prober performs a single health check against a component.
+type Prober interface {
+	Probe(ctx context.Context) Result
+}
+
+// Config describes a component's probe: how to reach it (Endpoint) and,
+// depending on Type, a type-specific Options payload.
+type Config struct {
+	Name     string
+	Endpoint string
+	Type     string
+	Options  json.RawMessage
+}
+
+// New builds the Prober indicated by cfg.Type. An empty Type defaults to
+// "http", which reproduces the monitor's original GET-and-expect-200-or-
+// {"status":"ok"} behavior so existing configs keep working unchanged.
+func New(cfg Config) (Prober, error) {
+	switch cfg.Type {
+	case "", "http":
+		return newHTTPProber(cfg)
+	case "tcp":
+		return newTCPProber(cfg)
+	case "icmp":
+		return newICMPProber(cfg)
+	case "dns":
+		return newDNSProber(cfg)
+	case "grpc":
+		return newGRPCProber(cfg)
+	case "exec":
+		return newExecProber(cfg)
+	default:
+		return nil, fmt.Errorf("probe: unknown type %q for component %q", cfg.Type, cfg.Name)
+	}
+}
@@ -0,0 +1,43 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TCPOptions configures the TCP prober, which simply opens and closes a
+// connection to cfg.Endpoint (host:port).
+type TCPOptions struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+type tcpProber struct {
+	endpoint string
+	timeout  time.Duration
+}
+
+func newTCPProber(cfg Config) (Prober, error) {
+	var opts TCPOptions
+	if len(cfg.Options) > 0 {
+		if err := json.Unmarshal(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("probe: tcp: invalid options: %w", err)
+		}
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &tcpProber{endpoint: cfg.Endpoint, timeout: opts.Timeout}, nil
+}
+
+func (p *tcpProber) Probe(ctx context.Context) Result {
+	d := net.Dialer{Timeout: p.timeout}
+	conn, err := d.DialContext(ctx, "tcp", p.endpoint)
+	if err != nil {
+		return Result{Up: false, Err: err, Detail: err.Error()}
+	}
+	conn.Close()
+	return Result{Up: true, Detail: "tcp connect ok"}
+}
@@ -0,0 +1,49 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestTCPProber_UpWhenListenerAccepts(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	p, err := New(Config{Endpoint: ln.Addr().String(), Type: "tcp"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); !result.Up {
+		t.Errorf("expected connecting to a live listener to be healthy, got %+v", result)
+	}
+}
+
+func TestTCPProber_DownWhenNothingListening(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() // nothing listens here anymore
+
+	p, err := New(Config{Endpoint: addr, Type: "tcp"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result := p.Probe(context.Background()); result.Up {
+		t.Errorf("expected connecting to a closed port to be unhealthy, got %+v", result)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"p-monitoring/events"
+	"p-monitoring/logging"
+	"p-monitoring/metrics"
+	"p-monitoring/scheduler"
+)
+
+// Registry owns the live set of component run loops. It lets operators
+// add, remove, or change monitored components without restarting the
+// process: Apply diffs a desired component list against what is
+// currently running, cancelling removed components' goroutines and
+// (re)starting new or changed ones.
+type Registry struct {
+	mu sync.Mutex
+
+	sched           *scheduler.Scheduler
+	statusMap       *StatusMap
+	logger          *logging.Logger
+	sink            metrics.Sink
+	bus             *events.Bus
+	defaultInterval time.Duration
+
+	cancels     map[string]context.CancelFunc
+	configs     map[string]ComponentConfig
+	generations map[string]int64
+	version     int64
+}
+
+// NewRegistry returns an empty Registry. defaultInterval is used for any
+// component that doesn't set its own interval_seconds.
+func NewRegistry(sched *scheduler.Scheduler, statusMap *StatusMap, logger *logging.Logger, sink metrics.Sink, bus *events.Bus, defaultInterval time.Duration) *Registry {
+	return &Registry{
+		sched:           sched,
+		statusMap:       statusMap,
+		logger:          logger,
+		sink:            sink,
+		bus:             bus,
+		defaultInterval: defaultInterval,
+		cancels:         make(map[string]context.CancelFunc),
+		configs:         make(map[string]ComponentConfig),
+		generations:     make(map[string]int64),
+	}
+}
+
+// Version returns the current topology version. It is bumped once by
+// every Add or Remove and once by every Apply that adds, removes, or
+// changes at least one component, so HTTP clients can tell from /health
+// whether the set of monitored components changed.
+func (r *Registry) Version() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.version
+}
+
+// Apply diffs desired against the currently running components: new ones
+// are started, ones no longer present are stopped, and ones whose config
+// changed are restarted with their new settings. Unchanged components
+// are left running undisturbed.
+func (r *Registry) Apply(desired []ComponentConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	seen := make(map[string]bool, len(desired))
+	for _, c := range desired {
+		seen[c.Name] = true
+		if existing, ok := r.configs[c.Name]; ok && componentConfigEqual(existing, c) {
+			continue
+		}
+		r.startLocked(c)
+		changed = true
+	}
+	for name := range r.configs {
+		if !seen[name] {
+			r.stopLocked(name)
+			changed = true
+		}
+	}
+	if changed {
+		r.version++
+	}
+}
+
+// Add starts (or restarts, if already running) a single component.
+func (r *Registry) Add(c ComponentConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.startLocked(c)
+	r.version++
+}
+
+// Remove stops a single component's run loop. It reports whether the
+// component was running.
+func (r *Registry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.configs[name]; !ok {
+		return false
+	}
+	r.stopLocked(name)
+	r.version++
+	return true
+}
+
+func (r *Registry) startLocked(c ComponentConfig) {
+	r.stopLocked(c.Name) // no-op if not currently running
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancels[c.Name] = cancel
+	r.configs[c.Name] = c
+	r.generations[c.Name]++
+	gen := r.generations[c.Name]
+
+	interval := r.defaultInterval
+	if c.IntervalSeconds > 0 {
+		interval = time.Duration(c.IntervalSeconds) * time.Second
+	}
+	timeout := 5 * time.Second
+	if c.TimeoutSeconds > 0 {
+		timeout = time.Duration(c.TimeoutSeconds) * time.Second
+	}
+	maxBackoff := time.Duration(c.MaxBackoffSeconds) * time.Second
+
+	opts := scheduler.Options{
+		Interval: interval,
+		Timeout:  timeout,
+		Jitter:   interval,
+		Breaker: scheduler.CircuitBreakerConfig{
+			FailureThreshold: c.FailureThreshold,
+			MaxBackoff:       maxBackoff,
+		},
+	}
+	r.sched.Run(ctx, opts, func(ctx context.Context) bool {
+		return runComponentCheck(ctx, c, r.statusMap, r.logger, r.sink, r.bus, func() bool {
+			return r.isCurrent(c.Name, gen)
+		})
+	}, func(open bool) {
+		breakerStatus := "circuit_closed"
+		if open {
+			breakerStatus = "circuit_open"
+		}
+		r.bus.Publish(events.StatusChanged, c.Name, "", breakerStatus, "")
+	})
+}
+
+func (r *Registry) stopLocked(name string) {
+	if cancel, ok := r.cancels[name]; ok {
+		cancel()
+		delete(r.cancels, name)
+	}
+	delete(r.configs, name)
+
+	r.statusMap.mu.Lock()
+	delete(r.statusMap.Components, name)
+	r.statusMap.mu.Unlock()
+}
+
+// isCurrent reports whether gen is still the run loop registered for name.
+// generations is never rolled back on stop, only bumped on (re)start, so a
+// goroutine from a stopped or superseded run observes a mismatch (or finds
+// name no longer in cancels at all) and knows its result must be dropped,
+// rather than resurrecting a removed component or clobbering a newer run's
+// write to statusMap.
+func (r *Registry) isCurrent(name string, gen int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cancels[name] != nil && r.generations[name] == gen
+}
+
+// componentConfigEqual reports whether two component configs describe
+// the same probe. ComponentConfig embeds a json.RawMessage, which isn't
+// comparable with ==, so this compares it by byte content instead.
+func componentConfigEqual(a, b ComponentConfig) bool {
+	return a.Name == b.Name &&
+		a.Endpoint == b.Endpoint &&
+		a.Type == b.Type &&
+		a.IntervalSeconds == b.IntervalSeconds &&
+		a.TimeoutSeconds == b.TimeoutSeconds &&
+		a.FailureThreshold == b.FailureThreshold &&
+		a.MaxBackoffSeconds == b.MaxBackoffSeconds &&
+		bytes.Equal(a.Options, b.Options)
+}
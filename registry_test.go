@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"p-monitoring/events"
+	"p-monitoring/logging"
+	"p-monitoring/metrics"
+	"p-monitoring/scheduler"
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	logger, err := logging.New(logging.Config{Directory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return NewRegistry(scheduler.New(10), NewStatusMap(), logger, metrics.NewSink(nil), events.NewBus(0), time.Second)
+}
+
+func TestRegistry_ApplyAddsChangesAndRemoves(t *testing.T) {
+	r := newTestRegistry(t)
+	t.Cleanup(func() { r.Apply(nil) })
+
+	a := ComponentConfig{Name: "a", Endpoint: "http://127.0.0.1:9/a"}
+	b := ComponentConfig{Name: "b", Endpoint: "http://127.0.0.1:9/b"}
+	r.Apply([]ComponentConfig{a, b})
+	if len(r.configs) != 2 {
+		t.Fatalf("expected 2 running components, got %d", len(r.configs))
+	}
+	if r.Version() != 1 {
+		t.Fatalf("expected version 1 after first apply, got %d", r.Version())
+	}
+
+	// Re-applying the same desired set is a no-op: no restarts, no version
+	// bump.
+	r.Apply([]ComponentConfig{a, b})
+	if r.Version() != 1 {
+		t.Errorf("expected version to stay 1 when nothing changed, got %d", r.Version())
+	}
+
+	// Removing b and adding c bumps the version and leaves only a and c
+	// running.
+	c := ComponentConfig{Name: "c", Endpoint: "http://127.0.0.1:9/c"}
+	r.Apply([]ComponentConfig{a, c})
+	if r.Version() != 2 {
+		t.Errorf("expected version 2 after removing b and adding c, got %d", r.Version())
+	}
+	if _, ok := r.configs["b"]; ok {
+		t.Errorf("expected b to be removed")
+	}
+	if _, ok := r.configs["c"]; !ok {
+		t.Errorf("expected c to be running")
+	}
+	if _, ok := r.configs["a"]; !ok {
+		t.Errorf("expected untouched a to still be running")
+	}
+}
+
+func TestRegistry_AddAndRemove(t *testing.T) {
+	r := newTestRegistry(t)
+	t.Cleanup(func() { r.Apply(nil) })
+
+	r.Add(ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/svc"})
+	if r.Version() != 1 {
+		t.Errorf("expected version 1 after Add, got %d", r.Version())
+	}
+	if _, ok := r.configs["svc"]; !ok {
+		t.Fatalf("expected svc to be registered after Add")
+	}
+
+	if ok := r.Remove("svc"); !ok {
+		t.Errorf("expected Remove to report true for a running component")
+	}
+	if r.Version() != 2 {
+		t.Errorf("expected version 2 after Remove, got %d", r.Version())
+	}
+	if _, ok := r.configs["svc"]; ok {
+		t.Errorf("expected svc to be unregistered after Remove")
+	}
+	r.statusMap.mu.RLock()
+	_, present := r.statusMap.Components["svc"]
+	r.statusMap.mu.RUnlock()
+	if present {
+		t.Errorf("expected svc to be deleted from statusMap after Remove")
+	}
+
+	if ok := r.Remove("svc"); ok {
+		t.Errorf("expected Remove to report false for an already-removed component")
+	}
+}
+
+func TestRegistry_RestartBumpsGeneration(t *testing.T) {
+	r := newTestRegistry(t)
+	t.Cleanup(func() { r.Apply(nil) })
+
+	r.mu.Lock()
+	r.startLocked(ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/v1"})
+	gen1 := r.generations["svc"]
+	r.mu.Unlock()
+
+	r.mu.Lock()
+	r.startLocked(ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/v2"})
+	gen2 := r.generations["svc"]
+	r.mu.Unlock()
+
+	if gen2 == gen1 {
+		t.Fatalf("expected restart to bump the generation, both runs got %d", gen1)
+	}
+	if r.isCurrent("svc", gen1) {
+		t.Errorf("expected the superseded generation %d to no longer be current", gen1)
+	}
+	if !r.isCurrent("svc", gen2) {
+		t.Errorf("expected the latest generation %d to be current", gen2)
+	}
+}
+
+// TestRegistry_StaleInFlightCheckDoesNotResurrectRemovedComponent is a
+// regression test for a race where a check already in flight when its
+// component is removed would still write its result to statusMap after
+// the fact, making a "removed" component reappear in /health forever.
+func TestRegistry_StaleInFlightCheckDoesNotResurrectRemovedComponent(t *testing.T) {
+	r := newTestRegistry(t)
+	t.Cleanup(func() { r.Apply(nil) })
+
+	c := ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/svc"}
+	r.mu.Lock()
+	r.startLocked(c)
+	gen := r.generations["svc"]
+	r.mu.Unlock()
+
+	// The component is removed while a check for `gen` is still in flight.
+	r.Remove("svc")
+
+	// The in-flight check now completes and tries to record its result
+	// using the generation it started with.
+	current := func() bool { return r.isCurrent("svc", gen) }
+	runComponentCheck(context.Background(), c, r.statusMap, r.logger, r.sink, r.bus, current)
+
+	r.statusMap.mu.RLock()
+	_, present := r.statusMap.Components["svc"]
+	r.statusMap.mu.RUnlock()
+	if present {
+		t.Errorf("expected the stale check's write to be dropped, but svc reappeared in statusMap")
+	}
+}
+
+// TestRegistry_StaleInFlightCheckDoesNotClobberRestartedResult covers the
+// restart variant of the same race: a check started before a restart must
+// not overwrite the result of the newer run.
+func TestRegistry_StaleInFlightCheckDoesNotClobberRestartedResult(t *testing.T) {
+	r := newTestRegistry(t)
+	t.Cleanup(func() { r.Apply(nil) })
+
+	c := ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/v1"}
+	r.mu.Lock()
+	r.startLocked(c)
+	staleGen := r.generations["svc"]
+	r.mu.Unlock()
+
+	// svc is restarted (e.g. its config changed) while the old generation's
+	// check is still in flight.
+	r.mu.Lock()
+	r.startLocked(ComponentConfig{Name: "svc", Endpoint: "http://127.0.0.1:9/v2"})
+	r.mu.Unlock()
+
+	r.statusMap.mu.Lock()
+	r.statusMap.Components["svc"] = HealthComponent{Name: "svc", Status: "ok"}
+	r.statusMap.mu.Unlock()
+
+	staleCurrent := func() bool { return r.isCurrent("svc", staleGen) }
+	runComponentCheck(context.Background(), c, r.statusMap, r.logger, r.sink, r.bus, staleCurrent)
+
+	r.statusMap.mu.RLock()
+	got := r.statusMap.Components["svc"]
+	r.statusMap.mu.RUnlock()
+	if got.Status != "ok" {
+		t.Errorf("expected the stale generation's check to leave the newer run's result untouched, got status %q", got.Status)
+	}
+}
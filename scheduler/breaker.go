@@ -0,0 +1,53 @@
+package scheduler
+
+import "time"
+
+// circuitBreaker tracks a consecutive-failure streak for one run loop and
+// decides when to back off exponentially instead of probing on the
+// normal interval.
+type circuitBreaker struct {
+	threshold  int
+	maxBackoff time.Duration
+
+	failures int
+	open     bool
+	backoffD time.Duration
+}
+
+// record updates the failure streak given the outcome of a check and
+// reports whether the breaker's open/closed state changed as a result.
+func (c *circuitBreaker) record(ok bool) (changed bool) {
+	if ok {
+		changed = c.open
+		c.failures = 0
+		c.open = false
+		c.backoffD = 0
+		return changed
+	}
+
+	c.failures++
+	if c.threshold <= 0 || c.failures < c.threshold {
+		return false
+	}
+
+	changed = !c.open
+	c.open = true
+	if c.backoffD == 0 {
+		c.backoffD = time.Second
+	} else {
+		c.backoffD *= 2
+	}
+	if c.maxBackoff > 0 && c.backoffD > c.maxBackoff {
+		c.backoffD = c.maxBackoff
+	}
+	return changed
+}
+
+// backoff returns the delay to use instead of the normal interval while
+// the breaker is open, or 0 if it is closed.
+func (c *circuitBreaker) backoff() time.Duration {
+	if c.open {
+		return c.backoffD
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAtThreshold(t *testing.T) {
+	c := &circuitBreaker{threshold: 3, maxBackoff: time.Minute}
+	if changed := c.record(false); changed {
+		t.Errorf("failure 1: expected no state change")
+	}
+	if changed := c.record(false); changed {
+		t.Errorf("failure 2: expected no state change")
+	}
+	if changed := c.record(false); !changed {
+		t.Errorf("failure 3: expected breaker to open")
+	}
+	if !c.open {
+		t.Errorf("expected breaker open after %d consecutive failures", c.threshold)
+	}
+	if c.backoff() != time.Second {
+		t.Errorf("expected initial backoff of 1s, got %v", c.backoff())
+	}
+}
+
+func TestCircuitBreaker_BackoffDoublesAndCaps(t *testing.T) {
+	c := &circuitBreaker{threshold: 1, maxBackoff: 4 * time.Second}
+	c.record(false) // opens, backoff = 1s
+	c.record(false) // backoff = 2s
+	if got := c.backoff(); got != 2*time.Second {
+		t.Errorf("expected backoff 2s, got %v", got)
+	}
+	c.record(false) // backoff = 4s
+	if got := c.backoff(); got != 4*time.Second {
+		t.Errorf("expected backoff 4s, got %v", got)
+	}
+	c.record(false) // would double to 8s, capped at maxBackoff
+	if got := c.backoff(); got != 4*time.Second {
+		t.Errorf("expected backoff capped at maxBackoff 4s, got %v", got)
+	}
+}
+
+func TestCircuitBreaker_ResetsOnSuccess(t *testing.T) {
+	c := &circuitBreaker{threshold: 2, maxBackoff: time.Minute}
+	c.record(false)
+	c.record(false) // opens
+	if !c.open {
+		t.Fatalf("expected breaker open before reset")
+	}
+	if changed := c.record(true); !changed {
+		t.Errorf("expected success to report a state change when closing an open breaker")
+	}
+	if c.open || c.backoff() != 0 {
+		t.Errorf("expected breaker closed with zero backoff after success, got open=%v backoff=%v", c.open, c.backoff())
+	}
+	if c.failures != 0 {
+		t.Errorf("expected failure streak reset to 0, got %d", c.failures)
+	}
+}
+
+func TestCircuitBreaker_ZeroThresholdNeverOpens(t *testing.T) {
+	c := &circuitBreaker{threshold: 0, maxBackoff: time.Minute}
+	for i := 0; i < 10; i++ {
+		if changed := c.record(false); changed {
+			t.Errorf("threshold<=0 should disable the breaker, but it opened on failure %d", i+1)
+		}
+	}
+	if c.open {
+		t.Errorf("expected breaker to stay closed with threshold<=0")
+	}
+}
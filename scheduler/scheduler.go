@@ -0,0 +1,108 @@
+// Package scheduler runs many independent check loops concurrently, each
+// on its own interval, while enforcing a global concurrency cap and a
+// per-loop circuit breaker so one slow or failing component can never
+// block or spam the others.
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// CircuitBreakerConfig controls backoff after consecutive check failures.
+// A zero FailureThreshold disables the breaker entirely.
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	MaxBackoff       time.Duration
+}
+
+// Options configures a single component's run loop.
+type Options struct {
+	Interval time.Duration
+	Timeout  time.Duration // 0 means the check gets ctx as-is, with no extra deadline
+	Jitter   time.Duration // upper bound of the random startup delay
+	Breaker  CircuitBreakerConfig
+}
+
+// CheckFunc performs one check attempt and reports whether it succeeded.
+type CheckFunc func(ctx context.Context) (ok bool)
+
+// StateChangeFunc is notified whenever a run loop's circuit breaker opens
+// or closes.
+type StateChangeFunc func(open bool)
+
+// Scheduler enforces a global concurrency cap across every run loop
+// started with Run.
+type Scheduler struct {
+	sem chan struct{}
+}
+
+// New returns a Scheduler that allows at most maxConcurrency checks to be
+// in flight at once across all run loops. maxConcurrency <= 0 falls back
+// to a default of 50.
+func New(maxConcurrency int) *Scheduler {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 50
+	}
+	return &Scheduler{sem: make(chan struct{}, maxConcurrency)}
+}
+
+// Run launches a goroutine that calls check on opts.Interval until ctx is
+// cancelled. It applies a random startup delay bounded by opts.Jitter to
+// avoid a thundering herd, and backs off per opts.Breaker once check
+// starts failing. onBreakerChange, if non-nil, fires whenever the breaker
+// opens or closes.
+func (s *Scheduler) Run(ctx context.Context, opts Options, check CheckFunc, onBreakerChange StateChangeFunc) {
+	go func() {
+		if opts.Jitter > 0 {
+			select {
+			case <-time.After(time.Duration(rand.Int63n(int64(opts.Jitter)))):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		breaker := &circuitBreaker{threshold: opts.Breaker.FailureThreshold, maxBackoff: opts.Breaker.MaxBackoff}
+		for {
+			if !s.acquire(ctx) {
+				return
+			}
+			checkCtx := ctx
+			cancel := context.CancelFunc(func() {})
+			if opts.Timeout > 0 {
+				checkCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			}
+			ok := check(checkCtx)
+			cancel()
+			s.release()
+
+			if breaker.record(ok) && onBreakerChange != nil {
+				onBreakerChange(breaker.open)
+			}
+
+			wait := opts.Interval
+			if backoff := breaker.backoff(); backoff > 0 {
+				wait = backoff
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) acquire(ctx context.Context) bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *Scheduler) release() {
+	<-s.sem
+}